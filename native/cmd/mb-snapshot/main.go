@@ -0,0 +1,144 @@
+// Command mb-snapshot takes and restores JetStream snapshots of an
+// embedded server started from a standard nats-server configuration file.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+
+	"github.com/four-robots/messagebroker.net/native/snapshot"
+)
+
+const serverReadyTimeout = 10 * time.Second
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	cmd, args := os.Args[1], os.Args[2:]
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	configFile := fs.String("config", "", "nats-server configuration file")
+	archive := fs.String("archive", "", "path to the snapshot archive")
+	global := fs.Bool("global", false, "also capture/restore operator config, account JWTs, and nkey seeds")
+	operatorJWTFile := fs.String("operator-jwt", "", "snapshot: file holding the operator JWT to embed under -global; restore: where to write it back out")
+	nkeySeedsDir := fs.String("nkey-seeds-dir", "", "snapshot: directory of <pubkey>.nk seed files to embed under -global; restore: where to write them back out")
+	include := fs.String("include", "", "comma-separated stream name patterns to include")
+	exclude := fs.String("exclude", "", "comma-separated stream name patterns to exclude")
+	if err := fs.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+
+	if *configFile == "" || *archive == "" {
+		fmt.Fprintln(os.Stderr, "-config and -archive are required")
+		os.Exit(2)
+	}
+
+	opts, err := server.ProcessConfigFile(*configFile)
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+	ns, err := server.NewServer(opts)
+	if err != nil {
+		log.Fatalf("new server: %v", err)
+	}
+	ns.Start()
+	defer ns.Shutdown()
+	if !ns.ReadyForConnections(serverReadyTimeout) {
+		log.Fatal("server did not become ready in time")
+	}
+
+	snapOpts := snapshot.Options{
+		Global:  *global,
+		Include: splitCSV(*include),
+		Exclude: splitCSV(*exclude),
+	}
+
+	ctx := context.Background()
+	switch cmd {
+	case "snapshot":
+		if *global && *operatorJWTFile != "" {
+			b, err := os.ReadFile(*operatorJWTFile)
+			if err != nil {
+				log.Fatalf("read operator jwt: %v", err)
+			}
+			snapOpts.GlobalOperatorJWT = string(b)
+		}
+		if *global && *nkeySeedsDir != "" {
+			seeds, err := loadNKeySeeds(*nkeySeedsDir)
+			if err != nil {
+				log.Fatalf("read nkey seeds: %v", err)
+			}
+			snapOpts.GlobalNKeySeeds = seeds
+		}
+		f, err := os.Create(*archive)
+		if err != nil {
+			log.Fatalf("create archive: %v", err)
+		}
+		defer f.Close()
+		if err := snapshot.Snapshot(ctx, f, ns, snapOpts); err != nil {
+			log.Fatalf("snapshot: %v", err)
+		}
+	case "restore":
+		snapOpts.GlobalOperatorJWTPath = *operatorJWTFile
+		snapOpts.GlobalNKeySeedsDir = *nkeySeedsDir
+		f, err := os.Open(*archive)
+		if err != nil {
+			log.Fatalf("open archive: %v", err)
+		}
+		defer f.Close()
+		if err := snapshot.Restore(ctx, f, ns, snapOpts); err != nil {
+			log.Fatalf("restore: %v", err)
+		}
+	default:
+		usage()
+	}
+}
+
+func loadNKeySeeds(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	seeds := map[string]string{}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".nk" {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		pub := strings.TrimSuffix(e.Name(), ".nk")
+		seeds[pub] = string(b)
+	}
+	return seeds, nil
+}
+
+func splitCSV(s string) []string {
+	if s == "" {
+		return nil
+	}
+	var out []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == ',' {
+			out = append(out, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(out, s[start:])
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: mb-snapshot (snapshot|restore) -config <file> -archive <path> [-global] [-operator-jwt <file>] [-nkey-seeds-dir <dir>] [-include p1,p2] [-exclude p1,p2]")
+	os.Exit(2)
+}
@@ -0,0 +1,15 @@
+// Package keystore generates and stores operator, account, and user nkey
+// seeds inside a TPM 2.0 device, sealed to a chosen set of PCRs, so a
+// deployment can boot the embedded server without any private key
+// material sitting on disk in the clear.
+//
+// The TPM is used for sealed storage and PCR-bound release policy, not as
+// a signing primitive: most TPM 2.0 parts have no ed25519 support, which
+// is what nkeys requires. Store.Load unseals a seed transiently, builds an
+// in-memory nkeys.KeyPair from it, and the returned KeyPair wipes that seed
+// from memory once Wipe is called.
+//
+// SoftwareStore is a drop-in implementation of the same interface for
+// development and for hosts without a TPM; its SealedSeed is the plain
+// nkeys seed, and it stores nothing itself.
+package keystore
@@ -0,0 +1,28 @@
+package keystore
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+)
+
+// MintAccountJWT builds and signs an account JWT using operatorKP as the
+// issuer, typically a key pair obtained from Store.Load so the operator's
+// seed never leaves this process in the clear. accountPub is the account's
+// public nkey (from an account key pair generated the same way).
+func MintAccountJWT(operatorKP nkeys.KeyPair, accountPub string, claims *jwt.AccountClaims) (string, error) {
+	if claims == nil {
+		claims = jwt.NewAccountClaims(accountPub)
+	}
+	claims.Subject = accountPub
+	if claims.IssuedAt == 0 {
+		claims.IssuedAt = time.Now().Unix()
+	}
+	token, err := claims.Encode(operatorKP)
+	if err != nil {
+		return "", fmt.Errorf("keystore: encode account jwt: %w", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,104 @@
+package keystore
+
+import (
+	"io"
+
+	"github.com/nats-io/nkeys"
+)
+
+// tpmKeyPair adapts an in-memory nkeys seed, obtained by unsealing a TPM
+// object, to the nkeys.KeyPair interface.
+//
+// Most TPM 2.0 parts implement no ed25519 support, which is what nkeys
+// requires, so Sign cannot delegate to TPM hardware signing the way an
+// RSA or ECDSA operation could: the seed has to be rebuilt into a
+// transient ed25519 key pair to sign, verify, or read the public key. To
+// keep the exposure window as small as possible, that derived key pair
+// is never cached on k and is wiped immediately after each call rather
+// than living for the lifetime of the KeyPair. See doc.go for the
+// package-level rationale.
+type tpmKeyPair struct {
+	seed []byte
+}
+
+func (k *tpmKeyPair) pair() (nkeys.KeyPair, error) {
+	return nkeys.FromSeed(k.seed)
+}
+
+func (k *tpmKeyPair) Seed() ([]byte, error) {
+	return cloneBytes(k.seed), nil
+}
+
+func (k *tpmKeyPair) PublicKey() (string, error) {
+	kp, err := k.pair()
+	if err != nil {
+		return "", err
+	}
+	defer kp.Wipe()
+	return kp.PublicKey()
+}
+
+func (k *tpmKeyPair) PrivateKey() ([]byte, error) {
+	kp, err := k.pair()
+	if err != nil {
+		return nil, err
+	}
+	defer kp.Wipe()
+	return kp.PrivateKey()
+}
+
+func (k *tpmKeyPair) Sign(input []byte) ([]byte, error) {
+	kp, err := k.pair()
+	if err != nil {
+		return nil, err
+	}
+	defer kp.Wipe()
+	return kp.Sign(input)
+}
+
+func (k *tpmKeyPair) Verify(input []byte, sig []byte) error {
+	kp, err := k.pair()
+	if err != nil {
+		return err
+	}
+	defer kp.Wipe()
+	return kp.Verify(input, sig)
+}
+
+// Seal, SealWithRand, and Open are only supported on nkeys.CurveKeyPair
+// (X25519); nkeys' own ed25519 key pairs reject them with
+// nkeys.ErrInvalidNKeyOperation, and tpmKeyPair does the same rather than
+// claiming support it doesn't have.
+
+func (k *tpmKeyPair) Seal(input []byte, recipient string) ([]byte, error) {
+	kp, err := k.pair()
+	if err != nil {
+		return nil, err
+	}
+	defer kp.Wipe()
+	return kp.Seal(input, recipient)
+}
+
+func (k *tpmKeyPair) SealWithRand(input []byte, recipient string, rr io.Reader) ([]byte, error) {
+	kp, err := k.pair()
+	if err != nil {
+		return nil, err
+	}
+	defer kp.Wipe()
+	return kp.SealWithRand(input, recipient, rr)
+}
+
+func (k *tpmKeyPair) Open(input []byte, sender string) ([]byte, error) {
+	kp, err := k.pair()
+	if err != nil {
+		return nil, err
+	}
+	defer kp.Wipe()
+	return kp.Open(input, sender)
+}
+
+func (k *tpmKeyPair) Wipe() {
+	wipe(k.seed)
+}
+
+var _ nkeys.KeyPair = (*tpmKeyPair)(nil)
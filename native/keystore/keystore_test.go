@@ -0,0 +1,64 @@
+package keystore
+
+import (
+	"testing"
+
+	"github.com/nats-io/nkeys"
+)
+
+// These cover the TPM-independent logic: the PCR bitmask math shared by
+// seal and unseal, and the Store contract via SoftwareStore, which
+// round-trips real nkeys seeds without needing a TPM device.
+//
+// TPMStore itself is covered by tpm_simulator_test.go against a real
+// go-tpm-tools simulator, gated behind the tpmsim build tag since the
+// simulator is slow to start and links against OpenSSL.
+
+func TestPCRSelectionSetsExpectedBits(t *testing.T) {
+	sel := pcrSelection([]int{0, 7, 8, 23})
+	if len(sel.PCRSelections) != 1 {
+		t.Fatalf("expected one bank, got %d", len(sel.PCRSelections))
+	}
+	mask := sel.PCRSelections[0].PCRSelect
+	for _, pcr := range []int{0, 7, 8, 23} {
+		if mask[pcr/8]&(1<<(pcr%8)) == 0 {
+			t.Fatalf("PCR %d not set in mask %08b", pcr, mask)
+		}
+	}
+	if mask[1]&^(1<<0) != 0 {
+		t.Fatalf("unexpected bits set in byte 1: %08b", mask[1])
+	}
+}
+
+func TestSoftwareStoreRoundTrip(t *testing.T) {
+	store := NewSoftwareStore()
+
+	kp, sealed, err := store.Generate(nkeys.PrefixByteAccount)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	wantPub, err := kp.PublicKey()
+	if err != nil {
+		t.Fatalf("PublicKey: %v", err)
+	}
+
+	loaded, err := store.Load(sealed)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	gotPub, err := loaded.PublicKey()
+	if err != nil {
+		t.Fatalf("PublicKey (loaded): %v", err)
+	}
+	if gotPub != wantPub {
+		t.Fatalf("public key mismatch after round-trip: got %s, want %s", gotPub, wantPub)
+	}
+
+	sig, err := loaded.Sign([]byte("hello"))
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if err := kp.Verify([]byte("hello"), sig); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package keystore
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nats-io/nkeys"
+)
+
+// Migrate imports an existing on-disk nkey seed file into store, returning
+// its sealed form. The caller is responsible for deleting seedFile once
+// satisfied the sealed seed round-trips through store.Load, since Migrate
+// itself never removes the source file.
+func Migrate(seedFile string, prefix nkeys.PrefixByte, store Store) (SealedSeed, error) {
+	raw, err := os.ReadFile(seedFile)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: read seed file: %w", err)
+	}
+	defer wipe(raw)
+
+	kp, err := nkeys.ParseDecoratedNKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: parse seed file: %w", err)
+	}
+	seed, err := kp.Seed()
+	if err != nil {
+		return nil, fmt.Errorf("keystore: read seed: %w", err)
+	}
+	defer wipe(seed)
+
+	switch s := store.(type) {
+	case *TPMStore:
+		return s.seal(prefix, seed)
+	case *SoftwareStore:
+		return SealedSeed(cloneBytes(seed)), nil
+	default:
+		return nil, fmt.Errorf("keystore: migrate: unsupported store type %T", store)
+	}
+}
@@ -0,0 +1,65 @@
+package keystore
+
+import (
+	"github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+)
+
+// pcrSelection builds the PCR selection used for both the trial policy
+// (to compute AuthPolicy at seal time) and the real policy session (to
+// satisfy it at unseal time).
+func pcrSelection(pcrs []int) tpm2.TPMLPCRSelection {
+	mask := make([]byte, 3)
+	for _, p := range pcrs {
+		mask[p/8] |= 1 << (p % 8)
+	}
+	return tpm2.TPMLPCRSelection{
+		PCRSelections: []tpm2.TPMSPCRSelection{{
+			Hash:      tpm2.TPMAlgSHA256,
+			PCRSelect: mask,
+		}},
+	}
+}
+
+// pcrPolicyDigest computes the policy digest a sealed object's AuthPolicy
+// must match in order to be releasable under the current PCR state, using
+// a trial session that is discarded afterwards.
+func pcrPolicyDigest(t transport.TPMCloser, pcrs []int) ([]byte, error) {
+	sess, closeSession, err := tpm2.PolicySession(t, tpm2.TPMAlgSHA256, 16, tpm2.Trial())
+	if err != nil {
+		return nil, err
+	}
+	defer closeSession()
+
+	if _, err := (tpm2.PolicyPCR{
+		PolicySession: sess.Handle(),
+		Pcrs:          pcrSelection(pcrs),
+	}).Execute(t); err != nil {
+		return nil, err
+	}
+
+	digest, err := tpm2.PolicyGetDigest{PolicySession: sess.Handle()}.Execute(t)
+	if err != nil {
+		return nil, err
+	}
+	return digest.PolicyDigest.Buffer, nil
+}
+
+// pcrPolicySession opens a real (non-trial) policy session satisfying the
+// PolicyPCR over pcrs, suitable for passing to Unseal. The caller must
+// invoke the returned close func once done with the session.
+func pcrPolicySession(t transport.TPMCloser, pcrs []int) (tpm2.Session, func() error, error) {
+	sess, closeSession, err := tpm2.PolicySession(t, tpm2.TPMAlgSHA256, 16)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := (tpm2.PolicyPCR{
+		PolicySession: sess.Handle(),
+		Pcrs:          pcrSelection(pcrs),
+	}).Execute(t); err != nil {
+		closeSession()
+		return nil, nil, err
+	}
+	return sess, closeSession, nil
+}
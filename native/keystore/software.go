@@ -0,0 +1,45 @@
+package keystore
+
+import (
+	"fmt"
+
+	"github.com/nats-io/nkeys"
+)
+
+// SoftwareStore is a drop-in Store for development hosts without a TPM. It
+// holds no state: the SealedSeed values it produces are the seed in nkeys'
+// own seed format, not anything TPM-sealed.
+//
+// It exists so application code can depend on the Store interface and
+// switch between TPMStore and SoftwareStore with a single constructor
+// call, typically chosen by a config flag or by probing for a TPM device.
+type SoftwareStore struct{}
+
+// NewSoftwareStore returns a SoftwareStore.
+func NewSoftwareStore() *SoftwareStore {
+	return &SoftwareStore{}
+}
+
+// Generate implements Store.
+func (s *SoftwareStore) Generate(prefix nkeys.PrefixByte) (nkeys.KeyPair, SealedSeed, error) {
+	kp, err := nkeys.CreatePair(prefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("keystore: create nkey pair: %w", err)
+	}
+	seed, err := kp.Seed()
+	if err != nil {
+		return nil, nil, fmt.Errorf("keystore: read seed: %w", err)
+	}
+	return kp, SealedSeed(cloneBytes(seed)), nil
+}
+
+// Load implements Store.
+func (s *SoftwareStore) Load(sealed SealedSeed) (nkeys.KeyPair, error) {
+	kp, err := nkeys.FromSeed([]byte(sealed))
+	if err != nil {
+		return nil, fmt.Errorf("keystore: decode seed: %w", err)
+	}
+	return kp, nil
+}
+
+var _ Store = (*SoftwareStore)(nil)
@@ -0,0 +1,26 @@
+package keystore
+
+import "github.com/nats-io/nkeys"
+
+// Store generates and loads nkeys.KeyPair values, delegating the private
+// key's storage and release policy to an implementation (TPM-backed or
+// Software).
+//
+// Generate returns an opaque SealedSeed, which Load later takes back: the
+// bytes a caller persists (to a file, a config map, etc.) in place of a raw
+// seed. A TPMStore's SealedSeed is useless without the TPM that sealed it;
+// a SoftwareStore's is the plain nkeys seed.
+type Store interface {
+	// Generate creates a new seed of the given nkeys prefix (operator,
+	// account, user, ...) and returns its sealed form alongside the
+	// resulting key pair.
+	Generate(prefix nkeys.PrefixByte) (nkeys.KeyPair, SealedSeed, error)
+
+	// Load unseals a previously generated SealedSeed and returns the
+	// corresponding key pair.
+	Load(sealed SealedSeed) (nkeys.KeyPair, error)
+}
+
+// SealedSeed is an opaque, storage-ready representation of a sealed seed.
+// Its contents are only meaningful to the Store that produced it.
+type SealedSeed []byte
@@ -0,0 +1,50 @@
+package keystore
+
+import "github.com/google/go-tpm/tpm2"
+
+// storagePrimaryTemplate is a standard restricted-decryption ECC storage
+// primary, used purely as the parent for our sealed data objects.
+var storagePrimaryTemplate = tpm2.TPMTPublic{
+	Type:    tpm2.TPMAlgECC,
+	NameAlg: tpm2.TPMAlgSHA256,
+	ObjectAttributes: tpm2.TPMAObject{
+		FixedTPM:            true,
+		FixedParent:         true,
+		SensitiveDataOrigin: true,
+		UserWithAuth:        true,
+		Restricted:          true,
+		Decrypt:             true,
+	},
+	Parameters: tpm2.NewTPMUPublicParms(
+		tpm2.TPMAlgECC,
+		&tpm2.TPMSECCParms{
+			Symmetric: tpm2.TPMTSymDefObject{
+				Algorithm: tpm2.TPMAlgAES,
+				KeyBits:   tpm2.NewTPMUSymKeyBits(tpm2.TPMAlgAES, tpm2.TPMKeyBits(128)),
+				Mode:      tpm2.NewTPMUSymMode(tpm2.TPMAlgAES, tpm2.TPMAlgCFB),
+			},
+			CurveID: tpm2.TPMECCNistP256,
+		},
+	),
+}
+
+// sealedDataTemplate describes a keyed-hash sealed-data object whose
+// release is gated behind policyDigest, produced from a PolicyPCR over the
+// PCRs the Store was configured with.
+func sealedDataTemplate(policyDigest []byte) tpm2.TPMTPublic {
+	return tpm2.TPMTPublic{
+		Type:    tpm2.TPMAlgKeyedHash,
+		NameAlg: tpm2.TPMAlgSHA256,
+		ObjectAttributes: tpm2.TPMAObject{
+			FixedTPM:    true,
+			FixedParent: true,
+		},
+		AuthPolicy: tpm2.TPM2BDigest{Buffer: policyDigest},
+		Parameters: tpm2.NewTPMUPublicParms(
+			tpm2.TPMAlgKeyedHash,
+			&tpm2.TPMSKeyedHashParms{
+				Scheme: tpm2.TPMTKeyedHashScheme{Scheme: tpm2.TPMAlgNull},
+			},
+		),
+	}
+}
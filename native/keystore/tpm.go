@@ -0,0 +1,168 @@
+package keystore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+	"github.com/nats-io/nkeys"
+)
+
+// TPMOptions configures a TPMStore.
+type TPMOptions struct {
+	// PCRs is the set of PCR indices the seal policy is bound to. A seed
+	// sealed under one PCR state cannot be unsealed after those registers
+	// change, e.g. across a firmware or bootloader update.
+	PCRs []int
+
+	// Hierarchy selects which TPM hierarchy owns the primary sealing key.
+	// Defaults to the owner hierarchy if zero.
+	Hierarchy tpm2.TPMHandle
+}
+
+// TPMStore implements Store against a real or simulated TPM 2.0 device.
+type TPMStore struct {
+	tpm  transport.TPMCloser
+	opts TPMOptions
+}
+
+// NewTPMStore opens a Store against dev, which may be a real TPM character
+// device or a go-tpm-tools simulator connection.
+func NewTPMStore(dev io.ReadWriteCloser, opts TPMOptions) (*TPMStore, error) {
+	if opts.Hierarchy == 0 {
+		opts.Hierarchy = tpm2.TPMRHOwner
+	}
+	return &TPMStore{tpm: transport.FromReadWriteCloser(dev), opts: opts}, nil
+}
+
+// Close releases the underlying TPM connection.
+func (s *TPMStore) Close() error {
+	return s.tpm.Close()
+}
+
+type sealedSeedPayload struct {
+	Prefix    nkeys.PrefixByte `json:"prefix"`
+	PCRs      []int            `json:"pcrs"`
+	Public    []byte           `json:"public"`
+	Private   []byte           `json:"private"`
+	ParentPub []byte           `json:"parent_public"`
+}
+
+// Generate implements Store.
+func (s *TPMStore) Generate(prefix nkeys.PrefixByte) (nkeys.KeyPair, SealedSeed, error) {
+	kp, err := nkeys.CreatePair(prefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("keystore: create nkey pair: %w", err)
+	}
+	seed, err := kp.Seed()
+	if err != nil {
+		return nil, nil, fmt.Errorf("keystore: read seed: %w", err)
+	}
+	defer wipe(seed)
+
+	sealed, err := s.seal(prefix, seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &tpmKeyPair{seed: cloneBytes(seed)}, sealed, nil
+}
+
+// Load implements Store.
+func (s *TPMStore) Load(sealed SealedSeed) (nkeys.KeyPair, error) {
+	var payload sealedSeedPayload
+	if err := json.Unmarshal(sealed, &payload); err != nil {
+		return nil, fmt.Errorf("keystore: decode sealed seed: %w", err)
+	}
+
+	seed, err := s.unseal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &tpmKeyPair{seed: seed}, nil
+}
+
+// seal creates a TPM2 sealed data object for seed under a primary storage
+// key in s.opts.Hierarchy, bound to a PolicyPCR over s.opts.PCRs.
+func (s *TPMStore) seal(prefix nkeys.PrefixByte, seed []byte) (SealedSeed, error) {
+	primary, err := tpm2.CreatePrimary{
+		PrimaryHandle: s.opts.Hierarchy,
+		InPublic:      tpm2.New2B(storagePrimaryTemplate),
+	}.Execute(s.tpm)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: create primary: %w", err)
+	}
+	defer flush(s.tpm, primary.ObjectHandle)
+
+	policy, err := pcrPolicyDigest(s.tpm, s.opts.PCRs)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := tpm2.Create{
+		ParentHandle: primary.ObjectHandle,
+		InSensitive:  tpm2.TPM2BSensitiveCreate{Sensitive: &tpm2.TPMSSensitiveCreate{Data: tpm2.NewTPMUSensitiveCreate(&tpm2.TPM2BSensitiveData{Buffer: seed})}},
+		InPublic:     tpm2.New2B(sealedDataTemplate(policy)),
+	}.Execute(s.tpm)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: seal: %w", err)
+	}
+
+	payload := sealedSeedPayload{
+		Prefix:    prefix,
+		PCRs:      s.opts.PCRs,
+		Public:    marshal2B(created.OutPublic),
+		Private:   marshal2B(created.OutPrivate),
+		ParentPub: marshal2B(primary.OutPublic),
+	}
+	return json.Marshal(payload)
+}
+
+func (s *TPMStore) unseal(payload sealedSeedPayload) ([]byte, error) {
+	primary, err := tpm2.CreatePrimary{
+		PrimaryHandle: s.opts.Hierarchy,
+		InPublic:      tpm2.New2B(storagePrimaryTemplate),
+	}.Execute(s.tpm)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: recreate primary: %w", err)
+	}
+	defer flush(s.tpm, primary.ObjectHandle)
+
+	pub, err := unmarshal2BPublic(payload.Public)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: decode sealed public area: %w", err)
+	}
+	priv, err := unmarshal2BPrivate(payload.Private)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: decode sealed private area: %w", err)
+	}
+
+	loaded, err := tpm2.Load{
+		ParentHandle: primary.ObjectHandle,
+		InPublic:     pub,
+		InPrivate:    priv,
+	}.Execute(s.tpm)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: load sealed object: %w", err)
+	}
+	defer flush(s.tpm, loaded.ObjectHandle)
+
+	session, closeSession, err := pcrPolicySession(s.tpm, payload.PCRs)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSession()
+
+	unsealed, err := tpm2.Unseal{ItemHandle: tpm2.NamedHandle{Handle: loaded.ObjectHandle}}.Execute(s.tpm, session)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: unseal (PCR state likely changed): %w", err)
+	}
+	return unsealed.OutData.Buffer, nil
+}
+
+func flush(t transport.TPMCloser, h tpm2.TPMHandle) {
+	_, _ = tpm2.FlushContext{FlushHandle: h}.Execute(t)
+}
+
+var _ Store = (*TPMStore)(nil)
@@ -0,0 +1,91 @@
+//go:build tpmsim
+
+package keystore
+
+import (
+	"testing"
+
+	"github.com/google/go-tpm-tools/simulator"
+	"github.com/google/go-tpm/tpm2"
+	"github.com/nats-io/nkeys"
+)
+
+// These exercise TPMStore against a real (software-emulated) TPM 2.0 device
+// via the go-tpm-tools simulator, rather than the TPM-independent fakes in
+// keystore_test.go. The simulator links against OpenSSL and is slow to
+// start, so it's gated behind the tpmsim build tag and skipped by a plain
+// `go test ./...`; run with `go test -tags tpmsim ./...`.
+
+func openSimulatorStore(t *testing.T) *TPMStore {
+	t.Helper()
+	sim, err := simulator.Get()
+	if err != nil {
+		t.Fatalf("open simulator: %v", err)
+	}
+	t.Cleanup(func() { _ = sim.Close() })
+
+	store, err := NewTPMStore(sim, TPMOptions{PCRs: []int{16}})
+	if err != nil {
+		t.Fatalf("NewTPMStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestTPMStoreRoundTrip(t *testing.T) {
+	store := openSimulatorStore(t)
+
+	kp, sealed, err := store.Generate(nkeys.PrefixByteAccount)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	wantPub, err := kp.PublicKey()
+	if err != nil {
+		t.Fatalf("PublicKey: %v", err)
+	}
+
+	loaded, err := store.Load(sealed)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	gotPub, err := loaded.PublicKey()
+	if err != nil {
+		t.Fatalf("PublicKey (loaded): %v", err)
+	}
+	if gotPub != wantPub {
+		t.Fatalf("public key mismatch after round-trip: got %s, want %s", gotPub, wantPub)
+	}
+
+	sig, err := loaded.Sign([]byte("hello"))
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if err := kp.Verify([]byte("hello"), sig); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+// TestTPMStoreUnsealFailsAfterPCRChange confirms the PCR-bound policy is
+// actually enforced by the TPM and not just advisory: extending PCR 16
+// (the one the store is sealed to) must make the existing SealedSeed
+// unreleasable.
+func TestTPMStoreUnsealFailsAfterPCRChange(t *testing.T) {
+	store := openSimulatorStore(t)
+
+	_, sealed, err := store.Generate(nkeys.PrefixByteAccount)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	extend := tpm2.PCREvent{
+		PCRHandle: tpm2.TPMHandle(16),
+		EventData: tpm2.TPM2BEvent{Buffer: []byte("keystore integration test")},
+	}
+	if _, err := extend.Execute(store.tpm); err != nil {
+		t.Fatalf("extend PCR16: %v", err)
+	}
+
+	if _, err := store.Load(sealed); err == nil {
+		t.Fatal("Load succeeded after PCR state changed; want an unseal error")
+	}
+}
@@ -0,0 +1,35 @@
+package keystore
+
+import "github.com/google/go-tpm/tpm2"
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+func cloneBytes(b []byte) []byte {
+	out := make([]byte, len(b))
+	copy(out, b)
+	return out
+}
+
+func marshal2B[T tpm2.Marshallable](v T) []byte {
+	return tpm2.Marshal(v)
+}
+
+func unmarshal2BPublic(b []byte) (tpm2.TPM2BPublic, error) {
+	out, err := tpm2.Unmarshal[tpm2.TPM2BPublic](b)
+	if err != nil {
+		return tpm2.TPM2BPublic{}, err
+	}
+	return *out, nil
+}
+
+func unmarshal2BPrivate(b []byte) (tpm2.TPM2BPrivate, error) {
+	out, err := tpm2.Unmarshal[tpm2.TPM2BPrivate](b)
+	if err != nil {
+		return tpm2.TPM2BPrivate{}, err
+	}
+	return *out, nil
+}
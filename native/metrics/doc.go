@@ -0,0 +1,8 @@
+// Package metrics exposes the embedded NATS server's runtime statistics
+// (/connz, /routez, /jsz, /accountz) as Prometheus collectors. /varz is
+// read on each scrape, but no metric is derived from it.
+//
+// Unlike a sidecar exporter scraping the monitoring HTTP port, the
+// Exporter in this package calls the server's internal stats accessors
+// directly, so no network round-trip is required to produce a scrape.
+package metrics
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Exporter is a prometheus.Collector that reports statistics pulled
+// directly from an embedded *server.Server. It is safe for concurrent use.
+type Exporter struct {
+	ns   *server.Server
+	opts MetricsOptions
+
+	mu       sync.Mutex
+	lastScrape time.Time
+	cache    *scrapeResult
+
+	descs exporterDescs
+}
+
+// scrapeResult holds the raw stats gathered from the server in a single
+// pass and reused for ScrapeInterval, even though it feeds several
+// Prometheus metric families. Per-account connection counts are not part
+// of it: Collect looks each account up with its own Accountz call.
+type scrapeResult struct {
+	varz      *server.Varz
+	connz     *server.Connz
+	routez    *server.Routez
+	jsz       *server.JSInfo
+	accountz  *server.Accountz
+}
+
+// NewExporter builds an Exporter around ns. Pass a zero-value
+// MetricsOptions to get DefaultMetricsOptions.
+func NewExporter(ns *server.Server, opts MetricsOptions) *Exporter {
+	if opts == (MetricsOptions{}) {
+		opts = DefaultMetricsOptions()
+	}
+	return &Exporter{
+		ns:    ns,
+		opts:  opts,
+		descs: newExporterDescs(),
+	}
+}
+
+// Register registers the Exporter on reg. It is a thin convenience wrapper
+// around reg.Register so callers can chain it with their own
+// registerer setup.
+func (e *Exporter) Register(reg prometheus.Registerer) error {
+	return reg.Register(e)
+}
+
+// Handler returns a pull-mode http.Handler serving this Exporter's metrics
+// for embedders who don't already run their own Prometheus registry.
+func (e *Exporter) Handler() http.Handler {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(e)
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+}
+
+// Describe implements prometheus.Collector.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	e.descs.describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	if !e.opts.Enabled {
+		return
+	}
+	res := e.scrape()
+	if res == nil {
+		return
+	}
+	collectConnz(e.descs, res.connz, ch)
+	collectRoutez(e.descs, res.routez, ch)
+	collectJsz(e.descs, res.jsz, e.opts, ch)
+	collectAccountz(e.descs, e.ns, res.accountz, e.opts, ch)
+}
+
+// scrape gathers a fresh snapshot of server stats, reusing the previous
+// snapshot if it is younger than ScrapeInterval.
+func (e *Exporter) scrape() *scrapeResult {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.cache != nil && time.Since(e.lastScrape) < e.opts.ScrapeInterval {
+		return e.cache
+	}
+
+	res := &scrapeResult{}
+	if v, err := e.ns.Varz(nil); err == nil {
+		res.varz = v
+	}
+	if c, err := e.ns.Connz(nil); err == nil {
+		res.connz = c
+	}
+	if r, err := e.ns.Routez(nil); err == nil {
+		res.routez = r
+	}
+	if j, err := e.ns.Jsz(&server.JSzOptions{Accounts: true, Streams: true, Consumer: true}); err == nil {
+		res.jsz = j
+	}
+	if a, err := e.ns.Accountz(nil); err == nil {
+		res.accountz = a
+	}
+
+	e.cache = res
+	e.lastScrape = time.Now()
+	return res
+}
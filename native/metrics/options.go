@@ -0,0 +1,39 @@
+package metrics
+
+import "time"
+
+// MetricsOptions controls how the Exporter scrapes the embedded server and
+// how much cardinality it is willing to produce.
+type MetricsOptions struct {
+	// Enabled turns the exporter on. DefaultMetricsOptions sets it to
+	// true, but defaults are only applied to an all-zero MetricsOptions: a
+	// partly filled one must set Enabled itself.
+	Enabled bool
+
+	// ScrapeInterval is how often in-process stats are refreshed between
+	// Prometheus scrapes. A zero value means stats are recomputed on every
+	// Collect call.
+	ScrapeInterval time.Duration
+
+	// MaxAccounts bounds the number of distinct accounts that get
+	// per-account JetStream gauges. Accounts beyond this limit are folded
+	// into an "other" bucket to keep series cardinality bounded on
+	// multi-tenant deployments.
+	MaxAccounts int
+
+	// MaxStreamsPerAccount bounds the number of distinct streams per
+	// account that get per-stream gauges, for the same reason as
+	// MaxAccounts.
+	MaxStreamsPerAccount int
+}
+
+// DefaultMetricsOptions returns the options used when a zero-value
+// MetricsOptions is passed to NewExporter.
+func DefaultMetricsOptions() MetricsOptions {
+	return MetricsOptions{
+		Enabled:              true,
+		ScrapeInterval:       10 * time.Second,
+		MaxAccounts:          256,
+		MaxStreamsPerAccount: 256,
+	}
+}
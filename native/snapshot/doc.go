@@ -0,0 +1,6 @@
+// Package snapshot takes and restores consistent point-in-time captures of
+// the JetStream state (streams, consumers, and KV buckets) managed by an
+// embedded server. Snapshots are written as a tar archive streamed through
+// zstd. Snapshot holds one stream's messages in memory at a time; Restore
+// reads every entry of the archive into memory before replaying any of it.
+package snapshot
@@ -0,0 +1,39 @@
+package snapshot
+
+import "time"
+
+// manifestName is the path of the manifest entry inside the archive. It is
+// written last, after every stream's data; Restore reads the whole archive
+// before acting on it, so the entry order does not matter to it.
+const manifestName = "manifest.json"
+
+// Manifest describes the contents of a snapshot archive.
+type Manifest struct {
+	CreatedAt time.Time                 `json:"created_at"`
+	Streams   map[string]StreamManifest `json:"streams"`
+	Global    *GlobalManifest           `json:"global,omitempty"`
+}
+
+// StreamManifest records what was captured for a single stream. Consumers
+// lists the names of the stream's consumers at snapshot time; their
+// configuration and delivery state are not captured, and Restore does not
+// recreate them.
+type StreamManifest struct {
+	Subjects  []string `json:"subjects"`
+	FirstSeq  uint64   `json:"first_seq"`
+	LastSeq   uint64   `json:"last_seq"`
+	Consumers []string `json:"consumers"`
+	// DataFile is the tar entry holding the stream's messages.
+	DataFile string `json:"data_file"`
+}
+
+// GlobalManifest records the operator-level material captured when
+// Options.Global is set.
+type GlobalManifest struct {
+	OperatorJWT string            `json:"operator_jwt,omitempty"`
+	AccountJWTs map[string]string `json:"account_jwts,omitempty"`
+	// NKeySeeds maps a key's public identity to its seed. Callers that
+	// don't want raw seeds in the archive should pair Global with an
+	// external keystore instead, see the keystore package.
+	NKeySeeds map[string]string `json:"nkey_seeds,omitempty"`
+}
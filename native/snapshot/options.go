@@ -0,0 +1,95 @@
+package snapshot
+
+// Options controls the scope of a Snapshot or Restore call.
+type Options struct {
+	// Include, if non-empty, restricts the snapshot to streams and KV
+	// buckets whose name matches one of these patterns (same semantics as
+	// JetStream stream subject filters).
+	Include []string
+
+	// Exclude drops streams and KV buckets matching these patterns, applied
+	// after Include.
+	Exclude []string
+
+	// Global also captures account JWTs, the operator JWT, and nkey seeds,
+	// so a Restore against a fresh node can rehydrate it completely rather
+	// than just its JetStream data. It is only consulted when Global is
+	// true.
+	Global bool
+
+	// GlobalOperatorJWT, GlobalNKeySeeds supply the operator-level material
+	// to embed in the archive under Global. The running server only ever
+	// holds the operator's decoded claims, not its signed JWT text, and
+	// never retains raw nkey seeds past startup, so these must come from
+	// the caller rather than be read back out of ns.
+	GlobalOperatorJWT string
+	GlobalNKeySeeds   map[string]string // public key -> seed
+
+	// GlobalOperatorJWTPath and GlobalNKeySeedsDir, used by Restore, are
+	// where the operator JWT and nkey seeds captured in the archive are
+	// written back out on disk for a fresh node's config to reference. An
+	// empty path skips writing that part of Global.
+	GlobalOperatorJWTPath string
+	GlobalNKeySeedsDir    string
+
+	// Incremental, when set, only captures messages with a stream sequence
+	// greater than the sequence recorded for that stream in Since.
+	Incremental bool
+
+	// Since maps stream name to the last sequence number already captured
+	// by a prior snapshot. It is only consulted when Incremental is true,
+	// and is typically populated from the manifest of the previous archive.
+	Since map[string]uint64
+}
+
+func (o Options) included(name string) bool {
+	if len(o.Include) > 0 && !matchAny(o.Include, name) {
+		return false
+	}
+	if matchAny(o.Exclude, name) {
+		return false
+	}
+	return true
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := subjectMatch(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// subjectMatch reports whether name matches the NATS-style subject pattern
+// p (supporting the '*' and '>' wildcards over '.'-delimited tokens).
+func subjectMatch(p, name string) (bool, error) {
+	if p == name {
+		return true, nil
+	}
+	pt, nt := splitTokens(p), splitTokens(name)
+	for i, tok := range pt {
+		if i >= len(nt) {
+			return false, nil
+		}
+		if tok == ">" {
+			return true, nil
+		}
+		if tok != "*" && tok != nt[i] {
+			return false, nil
+		}
+	}
+	return len(pt) == len(nt), nil
+}
+
+func splitTokens(s string) []string {
+	var toks []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '.' {
+			toks = append(toks, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(toks, s[start:])
+}
@@ -0,0 +1,150 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Restore reads an archive produced by Snapshot from r and replays it
+// against ns, recreating any stream that does not already exist and
+// republishing its captured messages in sequence order.
+func Restore(ctx context.Context, r io.Reader, ns *server.Server, opts Options) error {
+	nc, err := inProcessConn(ns)
+	if err != nil {
+		return fmt.Errorf("restore: connect: %w", err)
+	}
+	defer nc.Close()
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return fmt.Errorf("restore: jetstream: %w", err)
+	}
+
+	zr, err := zstd.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("restore: zstd reader: %w", err)
+	}
+	defer zr.Close()
+
+	tr := tar.NewReader(zr)
+
+	var manifest *Manifest
+	streamBodies := map[string][]byte{}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("restore: tar: %w", err)
+		}
+		if hdr.Name == manifestName {
+			var m Manifest
+			if err := json.NewDecoder(tr).Decode(&m); err != nil {
+				return fmt.Errorf("restore: manifest: %w", err)
+			}
+			manifest = &m
+			continue
+		}
+		buf := &bytes.Buffer{}
+		if _, err := io.Copy(buf, tr); err != nil {
+			return fmt.Errorf("restore: reading %s: %w", hdr.Name, err)
+		}
+		streamBodies[hdr.Name] = buf.Bytes()
+	}
+
+	if manifest == nil {
+		return fmt.Errorf("restore: archive is missing %s", manifestName)
+	}
+
+	for name, sm := range manifest.Streams {
+		if !opts.included(name) {
+			continue
+		}
+		if err := restoreStream(ctx, js, name, sm, streamBodies[sm.DataFile]); err != nil {
+			return fmt.Errorf("restore: stream %s: %w", name, err)
+		}
+	}
+
+	if opts.Global && manifest.Global != nil {
+		if err := restoreGlobal(ns, opts, manifest.Global); err != nil {
+			return fmt.Errorf("restore: global: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// restoreGlobal pushes the account JWTs captured by Snapshot back into ns's
+// live account resolver, and writes the operator JWT and nkey seeds out to
+// the paths opts asks for so a fresh node's config can point at them. ns's
+// resolver only accepts JWTs for accounts it already trusts under the
+// current operator, so this is the rehydration step that has to run after
+// the new node is already configured with that operator.
+func restoreGlobal(ns *server.Server, opts Options, gm *GlobalManifest) error {
+	if resolver := ns.AccountResolver(); resolver != nil {
+		for name, token := range gm.AccountJWTs {
+			if err := resolver.Store(name, token); err != nil {
+				return fmt.Errorf("store account %s jwt: %w", name, err)
+			}
+		}
+	}
+
+	if opts.GlobalOperatorJWTPath != "" && gm.OperatorJWT != "" {
+		if err := os.WriteFile(opts.GlobalOperatorJWTPath, []byte(gm.OperatorJWT), 0o600); err != nil {
+			return fmt.Errorf("write operator jwt: %w", err)
+		}
+	}
+
+	if opts.GlobalNKeySeedsDir != "" {
+		for pub, seed := range gm.NKeySeeds {
+			path := filepath.Join(opts.GlobalNKeySeedsDir, pub+".nk")
+			if err := os.WriteFile(path, []byte(seed), 0o600); err != nil {
+				return fmt.Errorf("write nkey seed %s: %w", pub, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func restoreStream(ctx context.Context, js jetstream.JetStream, name string, sm StreamManifest, body []byte) error {
+	if _, err := js.Stream(ctx, name); err != nil {
+		if _, err := js.CreateStream(ctx, jetstream.StreamConfig{
+			Name:     name,
+			Subjects: sm.Subjects,
+		}); err != nil {
+			return fmt.Errorf("create stream: %w", err)
+		}
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(body))
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var rec storedMessage
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return fmt.Errorf("decode message seq: %w", err)
+		}
+		msg := &nats.Msg{Subject: rec.Subject, Data: rec.Data}
+		if len(rec.Header) > 0 {
+			msg.Header = nats.Header(rec.Header)
+		}
+		if _, err := js.PublishMsg(ctx, msg); err != nil {
+			return fmt.Errorf("replay seq %d: %w", rec.Seq, err)
+		}
+	}
+	return sc.Err()
+}
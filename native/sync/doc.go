@@ -0,0 +1,12 @@
+// Package sync provides distributed mutual exclusion and leader election
+// built directly on the embedded server's JetStream KV, without requiring
+// an external coordination service.
+//
+// Locker uses per-key revisions for compare-and-swap: acquiring a lock is a
+// Create (if the key is absent) or an Update pinned to the last known
+// revision (if it has expired). The revision returned by a successful
+// acquire is handed back to the caller as a fencing token, so side effects
+// guarded by the lock can detect and reject a stale holder. Election layers
+// a campaign loop and a JetStream watch for change notification on top of
+// the same primitive.
+package sync
@@ -0,0 +1,118 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// ElectionOptions configures an Election.
+type ElectionOptions struct {
+	// TTL is passed through to the underlying Locker; see LockerOptions.TTL.
+	TTL time.Duration
+
+	// HolderID identifies this candidate in leader-change notifications.
+	HolderID string
+}
+
+// LeaderChange is delivered by Election.Observe whenever the holder of the
+// election key changes, including when it becomes vacant.
+type LeaderChange struct {
+	// Leader is the new leader's HolderID, or "" if the key is currently
+	// vacant.
+	Leader string
+	// Token is the fencing token of the new leader's lock, valid only when
+	// Leader is non-empty.
+	Token uint64
+}
+
+// Election runs leader election for a single key using a Locker under the
+// hood: the current leader is whoever holds the lock on that key.
+type Election struct {
+	locker *Locker
+	key    string
+}
+
+// NewElection returns an Election over key in kv. Pass a zero-value
+// ElectionOptions to get DefaultLockerOptions' TTL.
+func NewElection(kv jetstream.KeyValue, key string, opts ElectionOptions) *Election {
+	return &Election{
+		locker: NewLocker(kv, LockerOptions{TTL: opts.TTL, HolderID: opts.HolderID}),
+		key:    key,
+	}
+}
+
+// Campaign blocks until this candidate becomes leader or ctx is done,
+// returning the *Lock backing its leadership. Callers should hold onto the
+// Lock and call Resign (or Lock.Unlock) when stepping down voluntarily.
+func (e *Election) Campaign(ctx context.Context) (*Lock, error) {
+	return e.locker.Lock(ctx, e.key)
+}
+
+// Resign steps down from leadership, releasing lock so another candidate
+// can win the next Campaign.
+func (e *Election) Resign(ctx context.Context, lock *Lock) error {
+	if err := lock.Unlock(ctx); err != nil && !errors.Is(err, ErrNotHeld) {
+		return fmt.Errorf("sync: resign: %w", err)
+	}
+	return nil
+}
+
+// Observe streams leader-change events for this election's key until ctx is
+// done. It is implemented as a JetStream KV watch, so subscribers do not
+// poll.
+func (e *Election) Observe(ctx context.Context) (<-chan LeaderChange, error) {
+	w, err := e.locker.kv.Watch(ctx, e.key)
+	if err != nil {
+		return nil, fmt.Errorf("sync: observe: %w", err)
+	}
+
+	ch := make(chan LeaderChange, 1)
+	go func() {
+		defer close(ch)
+		defer w.Stop()
+		var lastLeader string
+		seen := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case entry, ok := <-w.Updates():
+				if !ok {
+					return
+				}
+				if entry == nil {
+					// End of initial-state replay; nothing to report yet.
+					continue
+				}
+				change := decodeLeaderChange(entry)
+				if seen && change.Leader == lastLeader {
+					// A renewal by the same leader; not a change.
+					continue
+				}
+				seen, lastLeader = true, change.Leader
+				select {
+				case ch <- change:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func decodeLeaderChange(entry jetstream.KeyValueEntry) LeaderChange {
+	if entry.Operation() != jetstream.KeyValuePut {
+		return LeaderChange{}
+	}
+	var rec lockRecord
+	if err := json.Unmarshal(entry.Value(), &rec); err != nil {
+		return LeaderChange{}
+	}
+	return LeaderChange{Leader: rec.Holder, Token: entry.Revision()}
+}
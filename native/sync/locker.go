@@ -0,0 +1,213 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	stdsync "sync"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// ErrLocked is returned by TryLock when the key is already held by a
+// non-expired holder.
+var ErrLocked = errors.New("sync: key is locked")
+
+// ErrNotHeld is returned by Unlock when the lock's fencing token no longer
+// matches the value stored in the KV bucket, meaning another holder took
+// the key after it expired, or the key is gone.
+var ErrNotHeld = errors.New("sync: lock is not held")
+
+// LockerOptions configures a Locker.
+type LockerOptions struct {
+	// TTL is how long a lock is valid without being renewed. A background
+	// renewer refreshes it at TTL/3 intervals for as long as the caller
+	// holds the *Lock.
+	TTL time.Duration
+
+	// HolderID identifies this process in the lock record, for
+	// diagnostics. Defaults to "holder-" followed by the current Unix time
+	// in nanoseconds if empty.
+	HolderID string
+}
+
+// DefaultLockerOptions returns the options used when a zero-value
+// LockerOptions is passed to NewLocker.
+func DefaultLockerOptions() LockerOptions {
+	return LockerOptions{TTL: 15 * time.Second}
+}
+
+// Locker implements mutual exclusion over keys in a single JetStream KV
+// bucket.
+type Locker struct {
+	kv   jetstream.KeyValue
+	opts LockerOptions
+}
+
+// NewLocker returns a Locker backed by kv. Pass a zero-value LockerOptions
+// to get DefaultLockerOptions.
+func NewLocker(kv jetstream.KeyValue, opts LockerOptions) *Locker {
+	if opts.TTL == 0 {
+		opts.TTL = DefaultLockerOptions().TTL
+	}
+	if opts.HolderID == "" {
+		opts.HolderID = newHolderID()
+	}
+	return &Locker{kv: kv, opts: opts}
+}
+
+// Lock is a held lock on a single key. The zero value is not usable; obtain
+// one from Locker.Lock or Locker.TryLock.
+type Lock struct {
+	locker *Locker
+	key    string
+
+	mu    stdsync.Mutex
+	token uint64 // fencing token: the KV revision of our lock record, guarded by mu
+
+	cancelRenew context.CancelFunc
+	renewDone   chan struct{} // closed once renewLoop has returned
+}
+
+// Token returns the fencing token for this lock: the KV revision of its
+// lock record as last written by this holder, i.e. the revision at which it
+// was acquired, advanced by each background renewal. Callers should pass
+// this alongside any externally
+// visible side effect so a later, stale holder's writes can be rejected.
+func (l *Lock) Token() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.token
+}
+
+type lockRecord struct {
+	Holder    string    `json:"holder"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Lock blocks until key can be acquired or ctx is done.
+func (lk *Locker) Lock(ctx context.Context, key string) (*Lock, error) {
+	backoff := 10 * time.Millisecond
+	for {
+		lock, err := lk.TryLock(ctx, key)
+		if err == nil {
+			return lock, nil
+		}
+		if !errors.Is(err, ErrLocked) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff < time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// TryLock makes a single acquisition attempt, returning ErrLocked
+// immediately if key is already held by a non-expired holder.
+func (lk *Locker) TryLock(ctx context.Context, key string) (*Lock, error) {
+	now := time.Now()
+	rec := lockRecord{Holder: lk.opts.HolderID, ExpiresAt: now.Add(lk.opts.TTL)}
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := lk.kv.Get(ctx, key)
+	switch {
+	case errors.Is(err, jetstream.ErrKeyNotFound):
+		rev, err := lk.kv.Create(ctx, key, payload)
+		if err != nil {
+			if errors.Is(err, jetstream.ErrKeyExists) {
+				return nil, ErrLocked
+			}
+			return nil, fmt.Errorf("sync: create lock: %w", err)
+		}
+		return lk.newHeldLock(ctx, key, rev), nil
+	case err != nil:
+		return nil, fmt.Errorf("sync: get lock: %w", err)
+	}
+
+	var existing lockRecord
+	if err := json.Unmarshal(entry.Value(), &existing); err != nil {
+		return nil, fmt.Errorf("sync: decode lock record: %w", err)
+	}
+	if now.Before(existing.ExpiresAt) {
+		return nil, ErrLocked
+	}
+
+	rev, err := lk.kv.Update(ctx, key, payload, entry.Revision())
+	if err != nil {
+		// Someone else raced us to steal the expired lock.
+		if errors.Is(err, jetstream.ErrKeyExists) {
+			return nil, ErrLocked
+		}
+		return nil, fmt.Errorf("sync: steal expired lock: %w", err)
+	}
+	return lk.newHeldLock(ctx, key, rev), nil
+}
+
+func (lk *Locker) newHeldLock(ctx context.Context, key string, revision uint64) *Lock {
+	l := &Lock{locker: lk, key: key, token: revision, renewDone: make(chan struct{})}
+	renewCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	l.cancelRenew = cancel
+	go lk.renewLoop(renewCtx, l)
+	return l
+}
+
+func (lk *Locker) renewLoop(ctx context.Context, l *Lock) {
+	defer close(l.renewDone)
+	ticker := time.NewTicker(lk.opts.TTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			lk.renew(ctx, l)
+		}
+	}
+}
+
+func (lk *Locker) renew(ctx context.Context, l *Lock) {
+	rec := lockRecord{Holder: lk.opts.HolderID, ExpiresAt: time.Now().Add(lk.opts.TTL)}
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	rev, err := lk.kv.Update(ctx, l.key, payload, l.Token())
+	if err != nil {
+		// Either we lost the lock to expiry/theft or the update failed
+		// transiently; keep the token as is and try again on the next
+		// tick. If the lock is gone, the next Unlock surfaces ErrNotHeld.
+		return
+	}
+	l.mu.Lock()
+	l.token = rev
+	l.mu.Unlock()
+}
+
+// Unlock releases the lock. It returns ErrNotHeld if another holder took
+// the key after the lock expired, or the key is gone; a lock that expired
+// but was not taken by anyone is still released without error.
+func (l *Lock) Unlock(ctx context.Context) error {
+	l.cancelRenew()
+	<-l.renewDone // wait for any in-flight renew to finish touching l.token
+	if err := l.locker.kv.Delete(ctx, l.key, jetstream.LastRevision(l.Token())); err != nil {
+		if errors.Is(err, jetstream.ErrKeyExists) || errors.Is(err, jetstream.ErrKeyNotFound) {
+			return ErrNotHeld
+		}
+		return fmt.Errorf("sync: unlock: %w", err)
+	}
+	return nil
+}
+
+func newHolderID() string {
+	return fmt.Sprintf("holder-%d", time.Now().UnixNano())
+}
@@ -0,0 +1,145 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	stdsync "sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// fakeKV is a minimal, in-memory stand-in for jetstream.KeyValue covering
+// only the methods Locker calls (Get, Create, Update, Delete); Election's
+// Watch is not covered.
+// Embedding the real interface lets it satisfy jetstream.KeyValue without
+// implementing every method; anything not overridden below would panic on
+// a nil receiver if called, which none of these tests do.
+type fakeKV struct {
+	jetstream.KeyValue
+
+	mu      stdsync.Mutex
+	entries map[string]fakeEntry
+	nextRev uint64
+}
+
+type fakeEntry struct {
+	value    []byte
+	revision uint64
+}
+
+func newFakeKV() *fakeKV {
+	return &fakeKV{entries: map[string]fakeEntry{}}
+}
+
+func (f *fakeKV) Get(_ context.Context, key string) (jetstream.KeyValueEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, ok := f.entries[key]
+	if !ok {
+		return nil, jetstream.ErrKeyNotFound
+	}
+	return fakeKVEntry{key: key, value: e.value, revision: e.revision}, nil
+}
+
+func (f *fakeKV) Create(_ context.Context, key string, value []byte) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.entries[key]; ok {
+		return 0, jetstream.ErrKeyExists
+	}
+	f.nextRev++
+	f.entries[key] = fakeEntry{value: value, revision: f.nextRev}
+	return f.nextRev, nil
+}
+
+func (f *fakeKV) Update(_ context.Context, key string, value []byte, last uint64) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, ok := f.entries[key]
+	if !ok || e.revision != last {
+		return 0, jetstream.ErrKeyExists
+	}
+	f.nextRev++
+	f.entries[key] = fakeEntry{value: value, revision: f.nextRev}
+	return f.nextRev, nil
+}
+
+func (f *fakeKV) Delete(_ context.Context, key string, _ ...jetstream.KVDeleteOpt) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.entries[key]; !ok {
+		return jetstream.ErrKeyNotFound
+	}
+	delete(f.entries, key)
+	return nil
+}
+
+type fakeKVEntry struct {
+	jetstream.KeyValueEntry
+	key      string
+	value    []byte
+	revision uint64
+}
+
+func (e fakeKVEntry) Key() string      { return e.key }
+func (e fakeKVEntry) Value() []byte    { return e.value }
+func (e fakeKVEntry) Revision() uint64 { return e.revision }
+
+func TestTryLockRejectsSecondHolder(t *testing.T) {
+	kv := newFakeKV()
+	locker := NewLocker(kv, LockerOptions{TTL: time.Hour})
+
+	lock, err := locker.TryLock(context.Background(), "job-1")
+	if err != nil {
+		t.Fatalf("first TryLock: %v", err)
+	}
+	defer lock.Unlock(context.Background())
+
+	if _, err := locker.TryLock(context.Background(), "job-1"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second TryLock: got %v, want ErrLocked", err)
+	}
+}
+
+func TestUnlockAfterRenewIsRaceFree(t *testing.T) {
+	kv := newFakeKV()
+	locker := NewLocker(kv, LockerOptions{TTL: 30 * time.Millisecond})
+
+	lock, err := locker.TryLock(context.Background(), "job-2")
+	if err != nil {
+		t.Fatalf("TryLock: %v", err)
+	}
+
+	// Let at least one background renew tick fire (TTL/3) before reading
+	// Token concurrently with Unlock; run with -race to catch the token
+	// field being touched without synchronization.
+	time.Sleep(20 * time.Millisecond)
+	go func() { _ = lock.Token() }()
+
+	if err := lock.Unlock(context.Background()); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+}
+
+func TestTryLockStealsExpiredLock(t *testing.T) {
+	kv := newFakeKV()
+	locker := NewLocker(kv, LockerOptions{TTL: 10 * time.Millisecond})
+
+	first, err := locker.TryLock(context.Background(), "job-3")
+	if err != nil {
+		t.Fatalf("first TryLock: %v", err)
+	}
+	first.cancelRenew() // stop the renewer so the record is left to expire
+	<-first.renewDone
+
+	time.Sleep(20 * time.Millisecond)
+
+	second, err := locker.TryLock(context.Background(), "job-3")
+	if err != nil {
+		t.Fatalf("steal TryLock: %v", err)
+	}
+	if second.Token() <= first.Token() {
+		t.Fatalf("fencing token did not advance: first=%d second=%d", first.Token(), second.Token())
+	}
+}
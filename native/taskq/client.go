@@ -0,0 +1,98 @@
+package taskq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nats-io/nuid"
+)
+
+// ErrDuplicateTask is returned by Client.Enqueue when the task's
+// UniqueKey is already held by a pending, scheduled, or in-retry task.
+var ErrDuplicateTask = errors.New("taskq: duplicate task")
+
+// Client enqueues tasks onto queues managed by a Server.
+type Client struct {
+	js     jetstream.JetStream
+	unique jetstream.KeyValue
+	sched  jetstream.KeyValue
+}
+
+// NewClient returns a Client that publishes through js, using the
+// "taskq_unique" and "taskq_scheduled" KV buckets for deduplication and
+// delayed delivery bookkeeping respectively. Both buckets must already
+// exist; see Setup, which creates them alongside the per-queue
+// streams.
+func NewClient(js jetstream.JetStream, unique, sched jetstream.KeyValue) *Client {
+	return &Client{js: js, unique: unique, sched: sched}
+}
+
+// Enqueue publishes t for delivery. If t.ProcessAt is in the future the
+// task is recorded in the scheduled bucket instead of being published to
+// the live work stream; Server's scheduler loop moves it over once due.
+func (c *Client) Enqueue(ctx context.Context, t *Task) (string, error) {
+	if t.Retry == (RetryPolicy{}) {
+		t.Retry = DefaultRetryPolicy
+	}
+	id := nuid.Next()
+
+	if t.UniqueKey != "" {
+		if _, err := c.unique.Create(ctx, t.UniqueKey, []byte(id)); err != nil {
+			if errors.Is(err, jetstream.ErrKeyExists) {
+				return "", ErrDuplicateTask
+			}
+			return "", fmt.Errorf("taskq: dedupe: %w", err)
+		}
+	}
+
+	if !t.ProcessAt.IsZero() && t.ProcessAt.After(time.Now()) {
+		if err := c.scheduleFor(ctx, id, t); err != nil {
+			return "", err
+		}
+		return id, nil
+	}
+
+	if err := c.publish(ctx, id, t, 0); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (c *Client) publish(ctx context.Context, id string, t *Task, attempt int) error {
+	msg := &nats.Msg{
+		Subject: streamSubject(t.Queue, t.Priority),
+		Data:    t.Payload,
+		Header:  nats.Header{},
+	}
+	msg.Header.Set(HeaderTaskID, id)
+	msg.Header.Set(HeaderQueue, t.Queue)
+	msg.Header.Set(HeaderAttempt, strconv.Itoa(attempt))
+	msg.Header.Set(HeaderMaxRetry, strconv.Itoa(t.Retry.MaxRetries))
+	msg.Header.Set(HeaderMinBackoff, t.Retry.MinBackoff.String())
+	msg.Header.Set(HeaderMaxBackoff, t.Retry.MaxBackoff.String())
+	msg.Header.Set(HeaderPriority, strconv.Itoa(int(t.Priority)))
+	if t.UniqueKey != "" {
+		msg.Header.Set(HeaderUniqueKey, t.UniqueKey)
+	}
+	if !t.Deadline.IsZero() {
+		msg.Header.Set(HeaderDeadline, t.Deadline.Format(time.RFC3339Nano))
+	}
+	if t.Type != "" {
+		msg.Subject = msg.Subject + "." + t.Type
+	}
+
+	_, err := c.js.PublishMsg(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("taskq: publish: %w", err)
+	}
+	return nil
+}
+
+func streamSubject(queue string, p Priority) string {
+	return fmt.Sprintf("taskq.%s.%d", queue, p)
+}
@@ -0,0 +1,13 @@
+// Package taskq layers a durable, at-least-once task queue on top of
+// JetStream, in the spirit of hibiken/asynq but native to this module: no
+// separate scheduler process or Redis dependency, just streams, consumers,
+// and KV buckets already provided by the embedded server.
+//
+// Redelivery is driven entirely by JetStream's AckWait and NakWithDelay,
+// with the retry policy carried in message headers rather than tracked by
+// a side process. The attempt header holds the count at publish time;
+// failures after that are taken from JetStream's delivery count, and no
+// next-visible-at time is stored on the message. Once a task exhausts its
+// retry policy it is republished to a per-queue dead-letter stream instead
+// of being Nak'd again.
+package taskq
@@ -0,0 +1,110 @@
+package taskq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Inspector provides read and cancel access to tasks in flight, without
+// needing a running Server.
+type Inspector struct {
+	js     jetstream.JetStream
+	sched  jetstream.KeyValue
+	unique jetstream.KeyValue
+}
+
+// NewInspector returns an Inspector sharing storage with a Client/Server
+// pair constructed via Setup.
+func NewInspector(js jetstream.JetStream, sched, unique jetstream.KeyValue) *Inspector {
+	return &Inspector{js: js, sched: sched, unique: unique}
+}
+
+// Scheduled lists tasks waiting for their ProcessAt to arrive.
+func (i *Inspector) Scheduled(ctx context.Context) ([]TaskInfo, error) {
+	keys, err := i.sched.Keys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("taskq: list scheduled: %w", err)
+	}
+	var out []TaskInfo
+	for _, id := range keys {
+		entry, err := i.sched.Get(ctx, id)
+		if err != nil {
+			continue
+		}
+		var rec scheduledRecord
+		if err := json.Unmarshal(entry.Value(), &rec); err != nil {
+			continue
+		}
+		out = append(out, TaskInfo{
+			ID: id, Queue: rec.Queue, Type: rec.Type, Payload: rec.Payload,
+			State: StateScheduled, ProcessAt: rec.ProcessAt,
+		})
+	}
+	return out, nil
+}
+
+// Pending lists tasks sitting in queue's live work stream that have not yet
+// been delivered to a consumer, i.e. have never been attempted.
+func (i *Inspector) Pending(ctx context.Context, queue string) ([]TaskInfo, error) {
+	return i.listWorkStream(ctx, queue, StatePending, func(attempt int) bool { return attempt == 0 })
+}
+
+// Retry lists tasks in queue's live work stream that failed at least once
+// and are waiting for redelivery via NakWithDelay.
+func (i *Inspector) Retry(ctx context.Context, queue string) ([]TaskInfo, error) {
+	return i.listWorkStream(ctx, queue, StateRetry, func(attempt int) bool { return attempt > 0 })
+}
+
+// Dead lists tasks that exhausted their retry policy and were routed to
+// queue's dead-letter stream.
+func (i *Inspector) Dead(ctx context.Context, queue string) ([]TaskInfo, error) {
+	str, err := i.js.Stream(ctx, deadStreamName(queue))
+	if err != nil {
+		return nil, fmt.Errorf("taskq: dead-letter stream %s: %w", queue, err)
+	}
+	return messagesToTaskInfo(ctx, str, queue, StateDead, nil)
+}
+
+func (i *Inspector) listWorkStream(ctx context.Context, queue string, state TaskState, keep func(attempt int) bool) ([]TaskInfo, error) {
+	str, err := i.js.Stream(ctx, streamName(queue))
+	if err != nil {
+		return nil, fmt.Errorf("taskq: stream %s: %w", queue, err)
+	}
+	return messagesToTaskInfo(ctx, str, queue, state, keep)
+}
+
+func messagesToTaskInfo(ctx context.Context, str jetstream.Stream, queue string, state TaskState, keep func(attempt int) bool) ([]TaskInfo, error) {
+	info, err := str.Info(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("taskq: stream info: %w", err)
+	}
+	var out []TaskInfo
+	for seq := info.State.FirstSeq; seq <= info.State.LastSeq; seq++ {
+		raw, err := str.GetMsg(ctx, seq)
+		if err != nil {
+			continue
+		}
+		attempt, _ := strconv.Atoi(raw.Header.Get(HeaderAttempt))
+		if keep != nil && !keep(attempt) {
+			continue
+		}
+		out = append(out, TaskInfo{
+			ID: raw.Header.Get(HeaderTaskID), Queue: queue, Payload: raw.Data, Attempt: attempt, State: state,
+		})
+	}
+	return out, nil
+}
+
+// CancelByID prevents a task from running once it is next delivered, by
+// writing a tombstone keyed on its UniqueKey. Tasks enqueued without a
+// UniqueKey cannot be cancelled this way.
+func (i *Inspector) CancelByID(ctx context.Context, uniqueKey string) error {
+	if _, err := i.unique.Put(ctx, tombstoneKey(uniqueKey), []byte("1")); err != nil {
+		return fmt.Errorf("taskq: cancel: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package taskq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// scheduledRecord is what Client.scheduleFor stores in the "taskq_scheduled"
+// KV bucket, keyed by task ID, until the scheduler loop moves it onto the
+// live work stream.
+type scheduledRecord struct {
+	Queue     string      `json:"queue"`
+	Type      string      `json:"type"`
+	Payload   []byte      `json:"payload"`
+	Priority  Priority    `json:"priority"`
+	Retry     RetryPolicy `json:"retry"`
+	UniqueKey string      `json:"unique_key,omitempty"`
+	ProcessAt time.Time   `json:"process_at"`
+	Deadline  time.Time   `json:"deadline"`
+}
+
+func (c *Client) scheduleFor(ctx context.Context, id string, t *Task) error {
+	rec := scheduledRecord{
+		Queue: t.Queue, Type: t.Type, Payload: t.Payload, Priority: t.Priority,
+		Retry: t.Retry, UniqueKey: t.UniqueKey, ProcessAt: t.ProcessAt,
+		Deadline: t.Deadline,
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("taskq: encode scheduled task: %w", err)
+	}
+	if _, err := c.sched.Put(ctx, id, b); err != nil {
+		return fmt.Errorf("taskq: schedule: %w", err)
+	}
+	return nil
+}
+
+// runScheduler periodically scans the scheduled bucket for tasks whose
+// ProcessAt has passed and publishes them to the live work stream. It runs
+// until ctx is done.
+func (s *Server) runScheduler(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.SchedulerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.scanScheduled(ctx)
+		}
+	}
+}
+
+func (s *Server) scanScheduled(ctx context.Context) {
+	keys, err := s.sched.Keys(ctx)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	for _, id := range keys {
+		entry, err := s.sched.Get(ctx, id)
+		if err != nil {
+			continue
+		}
+		var rec scheduledRecord
+		if err := json.Unmarshal(entry.Value(), &rec); err != nil {
+			continue
+		}
+		if rec.ProcessAt.After(now) {
+			continue
+		}
+		t := &Task{
+			Queue: rec.Queue, Type: rec.Type, Payload: rec.Payload,
+			Priority: rec.Priority, Retry: rec.Retry, UniqueKey: rec.UniqueKey,
+			Deadline: rec.Deadline,
+		}
+		if err := s.client.publish(ctx, id, t, 0); err != nil {
+			continue
+		}
+		_ = s.sched.Delete(ctx, id, jetstream.LastRevision(entry.Revision()))
+	}
+}
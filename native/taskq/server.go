@@ -0,0 +1,277 @@
+package taskq
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// HandlerFunc processes a single task's payload. Returning a non-nil error
+// causes the task to be retried (or dead-lettered once its retry policy is
+// exhausted); returning nil acknowledges it.
+type HandlerFunc func(ctx context.Context, payload []byte) error
+
+// ServerOptions configures a Server.
+type ServerOptions struct {
+	// Concurrency bounds how many tasks are processed at once across all
+	// registered handlers.
+	Concurrency int
+
+	// PriorityWeights gives the weighted round-robin share for each
+	// Priority when pulling from their respective consumers. A priority
+	// with no entry here is pulled with weight 1.
+	PriorityWeights map[Priority]int
+
+	// SchedulerInterval is how often the scheduled-task bucket is scanned
+	// for due tasks.
+	SchedulerInterval time.Duration
+}
+
+// DefaultServerOptions is used when a zero-value ServerOptions is passed to
+// NewServer.
+func DefaultServerOptions() ServerOptions {
+	return ServerOptions{
+		Concurrency:       20,
+		PriorityWeights:   map[Priority]int{PriorityLow: 1, PriorityDefault: 2, PriorityHigh: 4},
+		SchedulerInterval: time.Second,
+	}
+}
+
+// Server pulls tasks from one or more queues and dispatches them to
+// registered handlers.
+type Server struct {
+	js     jetstream.JetStream
+	client *Client
+	sched  jetstream.KeyValue
+	unique jetstream.KeyValue
+	opts   ServerOptions
+
+	mu       sync.Mutex
+	handlers map[string]HandlerFunc // queue -> handler
+}
+
+// NewServer returns a Server sharing js, sched, and unique with a Client
+// constructed the same way (typically the pair returned by Setup).
+func NewServer(js jetstream.JetStream, sched, unique jetstream.KeyValue, opts ServerOptions) *Server {
+	def := DefaultServerOptions()
+	if opts.Concurrency == 0 {
+		opts.Concurrency = def.Concurrency
+	}
+	if len(opts.PriorityWeights) == 0 {
+		opts.PriorityWeights = def.PriorityWeights
+	}
+	if opts.SchedulerInterval == 0 {
+		opts.SchedulerInterval = def.SchedulerInterval
+	}
+	return &Server{
+		js:       js,
+		client:   NewClient(js, unique, sched),
+		sched:    sched,
+		unique:   unique,
+		opts:     opts,
+		handlers: map[string]HandlerFunc{},
+	}
+}
+
+// Handle registers fn as the handler for queue. queue matches the
+// Client.Enqueue Task.Queue value; only exact queue names are supported,
+// there is no subject-style wildcarding.
+func (s *Server) Handle(queue string, fn HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[queue] = fn
+}
+
+// Run starts pulling and dispatching tasks for every registered queue,
+// along with the scheduled-task scanner, blocking until ctx is done.
+func (s *Server) Run(ctx context.Context) error {
+	go s.runScheduler(ctx)
+
+	s.mu.Lock()
+	queues := make([]string, 0, len(s.handlers))
+	for q := range s.handlers {
+		queues = append(queues, q)
+	}
+	s.mu.Unlock()
+
+	sem := make(chan struct{}, s.opts.Concurrency)
+	var wg sync.WaitGroup
+	for _, q := range queues {
+		for _, p := range []Priority{PriorityLow, PriorityDefault, PriorityHigh} {
+			wg.Add(1)
+			go func(queue string, prio Priority) {
+				defer wg.Done()
+				s.consumeLoop(ctx, queue, prio, sem)
+			}(q, p)
+		}
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (s *Server) consumeLoop(ctx context.Context, queue string, prio Priority, sem chan struct{}) {
+	cons, err := s.pullConsumer(ctx, queue, prio)
+	if err != nil {
+		return
+	}
+	weight := s.opts.PriorityWeights[prio]
+	if weight <= 0 {
+		weight = 1
+	}
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+		batch, err := cons.Fetch(weight, jetstream.FetchMaxWait(2*time.Second))
+		if err != nil {
+			continue
+		}
+		for msg := range batch.Messages() {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(m jetstream.Msg) {
+				defer func() { <-sem }()
+				s.handle(ctx, queue, m)
+			}(msg)
+		}
+	}
+}
+
+func (s *Server) pullConsumer(ctx context.Context, queue string, prio Priority) (jetstream.Consumer, error) {
+	str, err := s.js.Stream(ctx, streamName(queue))
+	if err != nil {
+		return nil, fmt.Errorf("taskq: stream %s: %w", queue, err)
+	}
+	return str.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable: fmt.Sprintf("taskq_%s_p%d", queue, prio),
+		// Tasks without a Type are published on the bare priority subject,
+		// typed ones on a further token below it.
+		FilterSubjects: []string{streamSubject(queue, prio), streamSubject(queue, prio) + ".>"},
+		AckPolicy:      jetstream.AckExplicitPolicy,
+		AckWait:        30 * time.Second,
+	})
+}
+
+func (s *Server) handle(ctx context.Context, queue string, msg jetstream.Msg) {
+	s.mu.Lock()
+	fn := s.handlers[queue]
+	s.mu.Unlock()
+	if fn == nil {
+		_ = msg.Nak()
+		return
+	}
+
+	if tombstoned(ctx, s.unique, msg) {
+		_ = msg.Ack()
+		return
+	}
+
+	hctx := ctx
+	if d := msg.Headers().Get(HeaderDeadline); d != "" {
+		if deadline, err := time.Parse(time.RFC3339Nano, d); err == nil {
+			var cancel context.CancelFunc
+			hctx, cancel = context.WithDeadline(ctx, deadline)
+			defer cancel()
+		}
+	}
+
+	if err := fn(hctx, msg.Data()); err != nil {
+		s.retryOrDeadLetter(ctx, queue, msg)
+		return
+	}
+	releaseUniqueKey(ctx, s.unique, msg)
+	_ = msg.Ack()
+}
+
+func (s *Server) retryOrDeadLetter(ctx context.Context, queue string, msg jetstream.Msg) {
+	attempt, _ := strconv.Atoi(msg.Headers().Get(HeaderAttempt))
+	maxRetry, _ := strconv.Atoi(msg.Headers().Get(HeaderMaxRetry))
+	// The attempt header is fixed at publish time and a Nak does not
+	// rewrite it, so the failed deliveries since then are counted from
+	// JetStream's delivery count.
+	if md, err := msg.Metadata(); err == nil {
+		attempt += int(md.NumDelivered)
+	} else {
+		attempt++
+	}
+
+	if attempt > maxRetry {
+		s.deadLetter(ctx, queue, msg)
+		return
+	}
+
+	minBackoff, _ := time.ParseDuration(msg.Headers().Get(HeaderMinBackoff))
+	maxBackoff, _ := time.ParseDuration(msg.Headers().Get(HeaderMaxBackoff))
+	delay := backoff(minBackoff, maxBackoff, attempt)
+	_ = msg.NakWithDelay(delay)
+}
+
+func (s *Server) deadLetter(ctx context.Context, queue string, msg jetstream.Msg) {
+	dead := &nats.Msg{Subject: deadLetterSubject(queue), Data: msg.Data(), Header: msg.Headers()}
+	if _, err := s.js.PublishMsg(ctx, dead); err != nil {
+		_ = msg.Nak()
+		return
+	}
+	releaseUniqueKey(ctx, s.unique, msg)
+	_ = msg.Ack()
+}
+
+func backoff(min, max time.Duration, attempt int) time.Duration {
+	if min <= 0 {
+		min = time.Second
+	}
+	d := min
+	for i := 0; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max && max > 0 {
+		d = max
+	}
+	return d
+}
+
+func tombstoned(ctx context.Context, unique jetstream.KeyValue, msg jetstream.Msg) bool {
+	key := msg.Headers().Get(HeaderUniqueKey)
+	if key == "" {
+		return false
+	}
+	entry, err := unique.Get(ctx, tombstoneKey(key))
+	return err == nil && entry != nil
+}
+
+// releaseUniqueKey removes msg's dedup record (see Client.Enqueue) once the
+// task it guards has reached a terminal state (acked or dead-lettered), so
+// the same UniqueKey can be enqueued again. It is a no-op for tasks
+// enqueued without a UniqueKey and for tasks still being retried.
+func releaseUniqueKey(ctx context.Context, unique jetstream.KeyValue, msg jetstream.Msg) {
+	key := msg.Headers().Get(HeaderUniqueKey)
+	if key == "" {
+		return
+	}
+	_ = unique.Delete(ctx, key)
+}
+
+func tombstoneKey(uniqueKey string) string {
+	return "cancel." + uniqueKey
+}
+
+func streamName(queue string) string {
+	return "TASKQ_" + queue
+}
+
+func deadStreamName(queue string) string {
+	return "TASKQ_DEAD_" + queue
+}
+
+func deadLetterSubject(queue string) string {
+	return fmt.Sprintf("taskq_dead.%s.msg", queue)
+}
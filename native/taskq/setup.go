@@ -0,0 +1,42 @@
+package taskq
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Setup creates the stream backing queue (if absent) and the shared
+// "taskq_unique"/"taskq_scheduled" KV buckets, returning a ready-to-use
+// Client. Call it once per queue name before constructing a Server with
+// NewServer against the same js.
+func Setup(ctx context.Context, js jetstream.JetStream, queue string) (*Client, error) {
+	if _, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     streamName(queue),
+		Subjects: []string{fmt.Sprintf("taskq.%s.>", queue)},
+	}); err != nil {
+		return nil, fmt.Errorf("taskq: create stream: %w", err)
+	}
+
+	// The dead-letter stream lives under its own subject namespace (rather
+	// than a ".dead" suffix inside taskq.<queue>.>) so it never shows up in
+	// the live work stream's Pending listing.
+	if _, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     deadStreamName(queue),
+		Subjects: []string{deadLetterSubject(queue)},
+	}); err != nil {
+		return nil, fmt.Errorf("taskq: create dead-letter stream: %w", err)
+	}
+
+	unique, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{Bucket: "taskq_unique"})
+	if err != nil {
+		return nil, fmt.Errorf("taskq: create unique bucket: %w", err)
+	}
+	sched, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{Bucket: "taskq_scheduled"})
+	if err != nil {
+		return nil, fmt.Errorf("taskq: create scheduled bucket: %w", err)
+	}
+
+	return NewClient(js, unique, sched), nil
+}
@@ -0,0 +1,108 @@
+package taskq
+
+import "time"
+
+// Header names used to carry redelivery and retry state on the NATS
+// message itself, so no separate scheduler process has to track it.
+const (
+	HeaderTaskID     = "Taskq-Task-Id"
+	HeaderQueue      = "Taskq-Queue"
+	HeaderAttempt    = "Taskq-Attempt"
+	HeaderMaxRetry   = "Taskq-Max-Retry"
+	HeaderMinBackoff = "Taskq-Min-Backoff"
+	HeaderMaxBackoff = "Taskq-Max-Backoff"
+	HeaderPriority   = "Taskq-Priority"
+	HeaderUniqueKey  = "Taskq-Unique-Key"
+	HeaderDeadline   = "Taskq-Deadline"
+)
+
+// Priority is a coarse scheduling class. Each queue is served in a weighted
+// round-robin across all three priorities, weighted by
+// ServerOptions.PriorityWeights.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityDefault
+	PriorityHigh
+)
+
+// RetryPolicy controls how a failed task is retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of redeliveries attempted before the task is
+	// routed to the dead-letter stream. A value of 0 disables retries; an
+	// all-zero RetryPolicy, though, is replaced by DefaultRetryPolicy at
+	// Enqueue, so 0 takes effect only with a non-zero MinBackoff or
+	// MaxBackoff.
+	MaxRetries int
+
+	// MinBackoff and MaxBackoff bound the exponential backoff applied
+	// between attempts; the delay passed to NakWithDelay is
+	// min(MaxBackoff, MinBackoff*2^attempt).
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is used when a Task is enqueued with a zero-value
+// RetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 25,
+	MinBackoff: time.Second,
+	MaxBackoff: 10 * time.Minute,
+}
+
+// Task is a unit of work to enqueue.
+type Task struct {
+	// Queue names the logical queue (JetStream stream) this task belongs
+	// to, e.g. "emails" or "billing".
+	Queue string
+
+	// Type, if set, is appended to the message subject as a final token so
+	// one queue can carry several kinds of work. It is not passed to the
+	// handler: Handle dispatches on Queue alone, and a HandlerFunc receives
+	// only the payload.
+	Type string
+
+	// Payload is the task's opaque body.
+	Payload []byte
+
+	// Priority selects which weighted consumer lane serves this task.
+	Priority Priority
+
+	// Retry overrides DefaultRetryPolicy for this task.
+	Retry RetryPolicy
+
+	// UniqueKey, if set, deduplicates enqueues: Client.Enqueue returns
+	// ErrDuplicateTask if a task with the same UniqueKey is already
+	// pending, scheduled, or in retry.
+	UniqueKey string
+
+	// ProcessAt schedules delivery for a future time instead of
+	// immediately. The zero value means "now".
+	ProcessAt time.Time
+
+	// Deadline, if set, is passed to the handler via context so long
+	// running work can respect it independent of AckWait.
+	Deadline time.Time
+}
+
+// TaskInfo is a read-only view of a task returned by the inspection API.
+type TaskInfo struct {
+	ID        string
+	Queue     string
+	Type      string
+	Payload   []byte
+	Attempt   int
+	State     TaskState
+	ProcessAt time.Time
+}
+
+// TaskState classifies where a task sits in its lifecycle.
+type TaskState int
+
+const (
+	StatePending TaskState = iota
+	StateScheduled
+	StateRetry
+	StateDead
+)